@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OverflowPolicy controls what happens when the async queue is full.
+type OverflowPolicy int
+
+const (
+	// Block waits for room in the queue, applying backpressure to the caller.
+	Block OverflowPolicy = iota
+	// DropNewest discards the record that didn't fit, keeping the queue as-is.
+	DropNewest
+	// DropOldest discards the oldest queued record to make room for the new one.
+	DropOldest
+)
+
+// logItem is what flows through the async queue: a formatted record to
+// write, an already-resolved sink dispatch to run, or a flush barrier (ack
+// is closed once everything queued ahead of it has been written).
+type logItem struct {
+	text string
+	fn   func()
+	ack  chan struct{}
+}
+
+// startAsync switches l into async mode: Debug/Info/... calls push formatted
+// records onto a buffered channel instead of writing inline, and a single
+// goroutine drains the channel, serializing all writes to the console and
+// file writers.
+func (l *StandardLogger) startAsync(bufferSize int, policy OverflowPolicy) {
+	l.async = true
+	l.overflowPolicy = policy
+	l.queue = make(chan logItem, bufferSize)
+	l.wg = &sync.WaitGroup{}
+
+	l.wg.Add(1)
+	go l.drainLoop()
+}
+
+func (l *StandardLogger) drainLoop() {
+	defer l.wg.Done()
+	for item := range l.queue {
+		switch {
+		case item.ack != nil:
+			close(item.ack)
+		case item.fn != nil:
+			item.fn()
+		default:
+			l.logger.Print(item.text)
+		}
+	}
+}
+
+// write sends a formatted record to its destination, either synchronously
+// or, in async mode, via the buffered queue.
+func (l *StandardLogger) write(text string) {
+	if !l.async {
+		l.logger.Print(text)
+		return
+	}
+	l.enqueue(logItem{text: text})
+}
+
+// enqueue applies the logger's OverflowPolicy when the queue is full.
+func (l *StandardLogger) enqueue(item logItem) {
+	select {
+	case l.queue <- item:
+		return
+	default:
+	}
+
+	switch l.overflowPolicy {
+	case DropNewest:
+		// Leave the queue as-is; this record is lost.
+	case DropOldest:
+		select {
+		case <-l.queue:
+		default:
+		}
+		select {
+		case l.queue <- item:
+		default:
+			// Someone else refilled the slot first; give up on this record.
+		}
+	default: // Block
+		l.queue <- item
+	}
+}
+
+// Flush blocks until every record enqueued so far has been written, or
+// timeout elapses. It is a no-op (returns nil immediately) when the logger
+// isn't running in async mode.
+func (l *StandardLogger) Flush(timeout time.Duration) error {
+	if !l.async {
+		return nil
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	ack := make(chan struct{})
+	select {
+	case l.queue <- logItem{ack: ack}:
+	case <-deadline.C:
+		return fmt.Errorf("logging: flush timed out waiting for queue capacity")
+	}
+
+	select {
+	case <-ack:
+		return nil
+	case <-deadline.C:
+		return fmt.Errorf("logging: flush timed out waiting for queue to drain")
+	}
+}
+
+// Flush blocks until every record enqueued so far has been written using
+// the default logger, or timeout elapses.
+func Flush(timeout time.Duration) error {
+	return GetLogger().Flush(timeout)
+}
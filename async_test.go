@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newAsyncTestLogger(policy OverflowPolicy, capacity int) *StandardLogger {
+	return &StandardLogger{
+		async:          true,
+		overflowPolicy: policy,
+		queue:          make(chan logItem, capacity),
+	}
+}
+
+func drainQueueTexts(q chan logItem) []string {
+	var out []string
+	for {
+		select {
+		case item := <-q:
+			out = append(out, item.text)
+		default:
+			return out
+		}
+	}
+}
+
+func TestEnqueueDropNewest(t *testing.T) {
+	l := newAsyncTestLogger(DropNewest, 2)
+	l.enqueue(logItem{text: "a"})
+	l.enqueue(logItem{text: "b"})
+	l.enqueue(logItem{text: "c"}) // queue is full; c should be dropped
+
+	got := drainQueueTexts(l.queue)
+	want := []string{"a", "b"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestEnqueueDropOldest(t *testing.T) {
+	l := newAsyncTestLogger(DropOldest, 2)
+	l.enqueue(logItem{text: "a"})
+	l.enqueue(logItem{text: "b"})
+	l.enqueue(logItem{text: "c"}) // queue is full; a should be evicted for c
+
+	got := drainQueueTexts(l.queue)
+	want := []string{"b", "c"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestEnqueueBlock(t *testing.T) {
+	l := newAsyncTestLogger(Block, 1)
+	l.enqueue(logItem{text: "a"})
+
+	done := make(chan struct{})
+	go func() {
+		l.enqueue(logItem{text: "b"}) // should block until "a" is drained
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("enqueue returned before the queue had room; Block should apply backpressure")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	<-l.queue // drain "a", freeing a slot
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("enqueue with Block never returned after room freed up")
+	}
+}
+
+func TestAsyncWriteAndFlush(t *testing.T) {
+	var buf bytes.Buffer
+	l := &StandardLogger{
+		logger:  log.New(&buf, "", 0),
+		sinksMu: &sync.RWMutex{},
+	}
+	l.startAsync(10, Block)
+	defer l.Close()
+
+	l.write("hello")
+	l.write("world")
+
+	if err := l.Flush(time.Second); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "hello") || !strings.Contains(got, "world") {
+		t.Errorf("buf = %q, want both records flushed before Flush returned", got)
+	}
+}
@@ -0,0 +1,71 @@
+//go:build !windows
+
+package logging
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// crashSignals are the fatal signals InstallCrashHandler watches for. These
+// indicate the process is already dying, so the handler re-raises them
+// after logging rather than trying to keep running.
+var crashSignals = []os.Signal{syscall.SIGSEGV, syscall.SIGABRT, syscall.SIGILL, syscall.SIGBUS}
+
+// InstallCrashHandler arranges for SIGSEGV/SIGABRT/SIGILL/SIGBUS to be
+// logged as a FATAL record, with a full multi-goroutine stack dump, through
+// the configured sinks before the process exits. It also enables
+// debug.SetPanicOnFault for the calling goroutine, so invalid memory
+// accesses from that goroutine raise a recoverable panic instead of going
+// straight to SIGSEGV; other goroutines are not affected. Call it once
+// during startup, after ConfigureLogging(WithConfig).
+func InstallCrashHandler() {
+	debug.SetPanicOnFault(true)
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, crashSignals...)
+
+	go func() {
+		sig := <-c
+		handleCrashSignal(sig)
+	}()
+}
+
+// handleCrashSignal logs the stack dump and then re-raises sig with the
+// default handler restored, so the process exits (and core-dumps, if
+// enabled) the way it would have without InstallCrashHandler.
+func handleCrashSignal(sig os.Signal) {
+	l := GetLogger()
+	stack := captureStack(true)
+	message := fmt.Sprintf("fatal signal received: %v", sig)
+	withStack := message + "\n" + stack
+
+	l.write(l.render(LevelFatal, withStack, 2))
+	l.dispatchSinks(LevelFatal, withStack, l.fields, 2)
+
+	captureSentryMessage(message, l.fields)
+	sentry.Flush(2 * time.Second)
+
+	if l.crashDumpDir != "" {
+		if err := writeCrashDumpFile(l.crashDumpDir, message, stack); err != nil {
+			l.Errorf("failed to write crash dump: %v", err)
+		}
+	}
+
+	// In async mode, drain the queue so the FATAL record is written before
+	// the re-raised signal ends the process.
+	if l.async {
+		l.Flush(2 * time.Second)
+	}
+
+	signal.Reset(sig)
+	if unixSig, ok := sig.(syscall.Signal); ok {
+		syscall.Kill(os.Getpid(), unixSig)
+	}
+}
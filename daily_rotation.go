@@ -0,0 +1,174 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultRotateSuffix is the time layout appended to the filename on daily
+// rotation, e.g. "app.log.2025-10-20".
+const defaultRotateSuffix = "2006-01-02"
+
+// RotationOptions configures a RotatingFileWriter. Zero values disable the
+// corresponding policy (e.g. Daily: false means no time-based rotation).
+type RotationOptions struct {
+	MaxBytes     int64  // Size threshold in bytes; 0 disables size-based rotation.
+	BackupCount  int    // Numbered backups to keep when Daily is false.
+	Daily        bool   // Rotate at the start of each day (midnight).
+	MaxDays      int    // Delete daily backups older than this many days; 0 keeps all.
+	MaxLines     int    // Rotate once the file reaches this many lines; 0 disables.
+	RotateSuffix string // time.Format layout for daily backup names; default "2006-01-02".
+	UTC          bool   // Compute the daily boundary in UTC instead of local time.
+}
+
+// NewRotatingFileWriterWithOptions creates a RotatingFileWriter with full
+// control over size, line-count, and daily/age-based rotation policy.
+func NewRotatingFileWriterWithOptions(filename string, opts RotationOptions) (*RotatingFileWriter, error) {
+	dir := filepath.Dir(filename)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	w := &RotatingFileWriter{
+		filename:     filename,
+		maxBytes:     opts.MaxBytes,
+		backupCount:  opts.BackupCount,
+		daily:        opts.Daily,
+		maxDays:      opts.MaxDays,
+		maxLines:     opts.MaxLines,
+		rotateSuffix: opts.RotateSuffix,
+		utcRotation:  opts.UTC,
+	}
+
+	if err := w.openFile(); err != nil {
+		return nil, err
+	}
+
+	if w.daily && w.maxDays > 0 {
+		pruneOldBackups(w.filename, w.rotateSuffixLayout(), w.maxDays, w.utcRotation)
+	}
+
+	return w, nil
+}
+
+func (w *RotatingFileWriter) rotateSuffixLayout() string {
+	if w.rotateSuffix != "" {
+		return w.rotateSuffix
+	}
+	return defaultRotateSuffix
+}
+
+// rotateDaily renames the current file to filename.<date>, using the date
+// of the boundary that just elapsed (i.e. the day the file covered). Daily
+// rotation can fire more than once per day alongside MaxBytes/MaxLines, so
+// if that name is already taken, a sequence number is appended rather than
+// overwriting the earlier backup.
+func (w *RotatingFileWriter) rotateDaily() {
+	boundaryDay := w.nextRotation
+	if boundaryDay.IsZero() {
+		boundaryDay = time.Now()
+	}
+	coveredDay := boundaryDay.AddDate(0, 0, -1)
+
+	backupName := fmt.Sprintf("%s.%s", w.filename, coveredDay.Format(w.rotateSuffixLayout()))
+	if _, err := os.Stat(backupName); err == nil {
+		for seq := 2; ; seq++ {
+			candidate := fmt.Sprintf("%s.%d", backupName, seq)
+			if _, err := os.Stat(candidate); os.IsNotExist(err) {
+				backupName = candidate
+				break
+			}
+		}
+	}
+	if _, err := os.Stat(w.filename); err == nil {
+		os.Rename(w.filename, backupName)
+	}
+}
+
+// nextRotationBoundary returns the next midnight after `from`, in local
+// time or UTC depending on utc.
+func nextRotationBoundary(from time.Time, utc bool) time.Time {
+	if utc {
+		from = from.UTC()
+	} else {
+		from = from.Local()
+	}
+	year, month, day := from.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, from.Location())
+	return midnight.AddDate(0, 0, 1)
+}
+
+// pruneOldBackups scans dir(filename) for backups named filename.<date>
+// (per layout) and removes any older than maxDays.
+func pruneOldBackups(filename, layout string, maxDays int, utc bool) {
+	dir := filepath.Dir(filename)
+	base := filepath.Base(filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	if utc {
+		now = now.UTC()
+	}
+	cutoff := now.AddDate(0, 0, -maxDays)
+
+	prefix := base + "."
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		suffix := strings.TrimPrefix(entry.Name(), prefix)
+		backupDate, err := time.Parse(layout, suffix)
+		if err != nil {
+			// rotateDaily appends ".<seq>" when more than one rotation lands
+			// on the same day; strip it and retry before giving up.
+			if dateOnly, _, ok := strings.Cut(suffix, "."); ok {
+				backupDate, err = time.Parse(layout, dateOnly)
+			}
+			if err != nil {
+				continue // not one of our daily backups (e.g. numbered backups)
+			}
+		}
+		if backupDate.Before(cutoff) {
+			os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+// countLines counts the newlines already present in filename, so a MaxLines
+// policy survives process restarts. It opens its own read-only handle since
+// the writer's handle is write-only.
+func countLines(filename string) int64 {
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	var count int64
+	reader := bufio.NewReader(file)
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := reader.Read(buf)
+		count += int64(bytes.Count(buf[:n], []byte{'\n'}))
+		if err != nil {
+			break
+		}
+	}
+	return count
+}
+
+// bytesCount counts occurrences of b in p without importing bytes at every
+// call site.
+func bytesCount(p []byte, b byte) int {
+	return bytes.Count(p, []byte{b})
+}
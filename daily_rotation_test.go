@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRotatingFileWriterDailyWithSizeThreshold reproduces a maintainer-filed
+// bug: combining Daily with MaxBytes used to have every size-triggered
+// rotation on the same day clobber the same filename.<date> backup.
+func TestRotatingFileWriterDailyWithSizeThreshold(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewRotatingFileWriterWithOptions(path, RotationOptions{
+		MaxBytes: 50,
+		Daily:    true,
+	})
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriterWithOptions: %v", err)
+	}
+	defer w.Close()
+
+	payload := []byte(
+		"0123456789012345678901234567890123456789\n", // 41 bytes
+	)
+	for i := 0; i < 5; i++ {
+		if _, err := w.Write(payload); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+
+	var totalBytes int64
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			t.Fatalf("Info: %v", err)
+		}
+		totalBytes += info.Size()
+	}
+
+	wantBytes := int64(len(payload) * 5)
+	if totalBytes != wantBytes {
+		t.Errorf("total bytes across rotated files = %d, want %d (some writes were clobbered); files: %v", totalBytes, wantBytes, entries)
+	}
+}
+
+func TestPruneOldBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	layout := defaultRotateSuffix
+
+	old := path + ".2000-01-01"
+	recent := path + "." + time.Now().Format(layout)
+
+	if err := os.WriteFile(old, []byte("old"), 0644); err != nil {
+		t.Fatalf("WriteFile old: %v", err)
+	}
+	if err := os.WriteFile(recent, []byte("recent"), 0644); err != nil {
+		t.Fatalf("WriteFile recent: %v", err)
+	}
+
+	pruneOldBackups(path, layout, 7, false)
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("expected old backup to be pruned, stat err = %v", err)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("expected recent backup to survive pruning, stat err = %v", err)
+	}
+}
@@ -13,6 +13,7 @@ This module provides:
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -46,6 +47,16 @@ const (
 	LevelFatal    LogLevel = "FATAL"
 )
 
+// LogFormat selects how a StandardLogger renders its records.
+type LogFormat string
+
+const (
+	// FormatText is the default bracketed "[ts] [LEVEL] [file:line] msg" format.
+	FormatText LogFormat = "text"
+	// FormatJSON emits one-line JSON objects suitable for log aggregators.
+	FormatJSON LogFormat = "json"
+)
+
 // getColor returns the ANSI color code for a log level
 func getColor(level LogLevel) string {
 	if !isTerminal() {
@@ -100,7 +111,55 @@ func formatLogMessage(level LogLevel, message string, skip int) string {
 	return fmt.Sprintf("%s [%s] [%s] %s", timestamp, levelStr, location, message)
 }
 
-// RotatingFileWriter implements log rotation based on file size
+// jsonRecord is the on-wire shape emitted by formatLogJSON.
+type jsonRecord struct {
+	Timestamp string                 `json:"ts"`
+	Level     string                 `json:"level"`
+	Caller    string                 `json:"caller"`
+	Message   string                 `json:"msg"`
+	Service   string                 `json:"service,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// formatLogJSON formats a log message as a single-line JSON object, for
+// ingestion by log aggregators. It mirrors formatLogMessage's caller
+// resolution via runtime.Caller(skip).
+func formatLogJSON(level LogLevel, message string, service string, fields []Field, skip int) string {
+	now := time.Now()
+
+	_, file, line, ok := runtime.Caller(skip)
+	location := "unknown:0"
+	if ok {
+		location = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+
+	var fieldMap map[string]interface{}
+	if len(fields) > 0 {
+		fieldMap = make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			fieldMap[f.Key] = f.Value
+		}
+	}
+
+	rec := jsonRecord{
+		Timestamp: now.UTC().Format(time.RFC3339Nano),
+		Level:     string(level),
+		Caller:    location,
+		Message:   message,
+		Service:   service,
+		Fields:    fieldMap,
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		// Fall back to a minimal record rather than dropping the log line.
+		return fmt.Sprintf(`{"ts":%q,"level":%q,"msg":%q}`, rec.Timestamp, rec.Level, "failed to marshal log record: "+err.Error())
+	}
+	return string(data)
+}
+
+// RotatingFileWriter implements log rotation based on file size, and
+// optionally daily rotation with age-based retention (see LogConfig.Daily).
 type RotatingFileWriter struct {
 	filename    string
 	maxBytes    int64
@@ -108,30 +167,24 @@ type RotatingFileWriter struct {
 	file        *os.File
 	currentSize int64
 	mu          sync.Mutex
+
+	// Daily/age-based rotation, configured via RotationOptions.
+	daily        bool
+	maxDays      int
+	maxLines     int
+	rotateSuffix string
+	utcRotation  bool
+	currentLines int64
+	nextRotation time.Time
 }
 
-// NewRotatingFileWriter creates a new rotating file writer
+// NewRotatingFileWriter creates a new rotating file writer that rotates
+// purely by size, keeping backupCount numbered backups (app.log.1, .2, ...).
 func NewRotatingFileWriter(filename string, maxBytes int64, backupCount int) (*RotatingFileWriter, error) {
-	// Create log directory if it doesn't exist
-	dir := filepath.Dir(filename)
-	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return nil, fmt.Errorf("failed to create log directory: %w", err)
-		}
-	}
-
-	w := &RotatingFileWriter{
-		filename:    filename,
-		maxBytes:    maxBytes,
-		backupCount: backupCount,
-	}
-
-	// Open the file
-	if err := w.openFile(); err != nil {
-		return nil, err
-	}
-
-	return w, nil
+	return NewRotatingFileWriterWithOptions(filename, RotationOptions{
+		MaxBytes:    maxBytes,
+		BackupCount: backupCount,
+	})
 }
 
 func (w *RotatingFileWriter) openFile() error {
@@ -149,6 +202,10 @@ func (w *RotatingFileWriter) openFile() error {
 
 	w.file = file
 	w.currentSize = info.Size()
+	w.currentLines = countLines(w.filename)
+	if w.daily {
+		w.nextRotation = nextRotationBoundary(time.Now(), w.utcRotation)
+	}
 	return nil
 }
 
@@ -156,8 +213,7 @@ func (w *RotatingFileWriter) Write(p []byte) (n int, err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	// Check if we need to rotate
-	if w.currentSize+int64(len(p)) > w.maxBytes {
+	if w.needsRotation(p) {
 		if err := w.rotate(); err != nil {
 			return 0, err
 		}
@@ -165,15 +221,51 @@ func (w *RotatingFileWriter) Write(p []byte) (n int, err error) {
 
 	n, err = w.file.Write(p)
 	w.currentSize += int64(n)
+	w.currentLines += int64(bytesCount(p, '\n'))
 	return n, err
 }
 
+// needsRotation reports whether the next write should trigger a rotation,
+// based on whichever of size, line count, and daily boundary are configured.
+func (w *RotatingFileWriter) needsRotation(p []byte) bool {
+	if w.maxBytes > 0 && w.currentSize+int64(len(p)) > w.maxBytes {
+		return true
+	}
+	if w.maxLines > 0 && w.currentLines+int64(bytesCount(p, '\n')) > int64(w.maxLines) {
+		return true
+	}
+	if w.daily && !w.nextRotation.IsZero() && !time.Now().Before(w.nextRotation) {
+		return true
+	}
+	return false
+}
+
 func (w *RotatingFileWriter) rotate() error {
 	// Close current file
 	if w.file != nil {
 		w.file.Close()
 	}
 
+	if w.daily {
+		w.rotateDaily()
+	} else {
+		w.rotateNumbered()
+	}
+
+	// Open new file
+	w.currentSize = 0
+	w.currentLines = 0
+	if err := w.openFile(); err != nil {
+		return err
+	}
+
+	if w.maxDays > 0 {
+		pruneOldBackups(w.filename, w.rotateSuffixLayout(), w.maxDays, w.utcRotation)
+	}
+	return nil
+}
+
+func (w *RotatingFileWriter) rotateNumbered() {
 	// Rotate existing backup files
 	// app.log -> app.log.1, app.log.1 -> app.log.2, etc.
 	for i := w.backupCount - 1; i >= 1; i-- {
@@ -194,10 +286,6 @@ func (w *RotatingFileWriter) rotate() error {
 	if _, err := os.Stat(w.filename); err == nil {
 		os.Rename(w.filename, fmt.Sprintf("%s.1", w.filename))
 	}
-
-	// Open new file
-	w.currentSize = 0
-	return w.openFile()
 }
 
 func (w *RotatingFileWriter) Close() error {
@@ -215,6 +303,29 @@ type StandardLogger struct {
 	serviceName string
 	logger      *log.Logger
 	fileWriter  *RotatingFileWriter
+	format      LogFormat
+	fields      []Field
+
+	// Async pipeline state, set up by startAsync when LogConfig.Async is true.
+	// Shared across loggers derived from the same root via With().
+	async          bool
+	overflowPolicy OverflowPolicy
+	queue          chan logItem
+	wg             *sync.WaitGroup
+
+	// Additional sinks registered via AddSink or LogConfig.Sinks, fanned
+	// out to on every record alongside the console/file output above.
+	sinksMu *sync.RWMutex
+	sinks   []sinkBinding
+
+	// Per-callsite sampling (LogConfig.Sampling) and a shared token bucket
+	// capping the rate of Sentry captures (LogConfig.SentryRateLimit).
+	sampling      SamplingConfig
+	sentryLimiter *tokenBucket
+
+	// crashDumpDir, if set from LogConfig.CrashDumpDir, also drops Fatal and
+	// InstallCrashHandler stack traces as a standalone timestamped+pid file.
+	crashDumpDir string
 }
 
 var defaultLogger *StandardLogger
@@ -222,9 +333,56 @@ var defaultLogger *StandardLogger
 // LogConfig contains configuration for logging
 type LogConfig struct {
 	ServiceName string
-	LogFile     string // Optional: path to log file for file logging with rotation
-	MaxBytes    int64  // Maximum size per log file in bytes (default: 100MB)
-	BackupCount int    // Number of backup files to keep (default: 5)
+	LogFile     string    // Optional: path to log file for file logging with rotation
+	MaxBytes    int64     // Maximum size per log file in bytes (default: 100MB)
+	BackupCount int       // Number of backup files to keep (default: 5)
+	LogFormat   LogFormat // Output format: FormatText (default) or FormatJSON
+
+	// Daily enables time-based rotation at midnight instead of (or in
+	// addition to) the MaxBytes size threshold. When set, backups are named
+	// with RotateSuffix rather than numbered.
+	Daily        bool
+	MaxDays      int    // Delete daily backups older than this many days; 0 keeps all.
+	MaxLines     int    // Rotate once the file reaches this many lines; 0 disables.
+	RotateSuffix string // time.Format layout for daily backup names, e.g. "2006-01-02" (default).
+	RotateUTC    bool   // Compute the daily rotation boundary in UTC instead of local time.
+
+	// Async moves writes off the calling goroutine onto a single writer
+	// goroutine fed by a buffered channel, so Debug/Info/... calls don't
+	// block on file or console IO.
+	Async          bool
+	BufferSize     int            // Queue capacity when Async is true (default: 1000).
+	OverflowPolicy OverflowPolicy // Behavior when the queue is full (default: Block).
+
+	// Sinks are registered in addition to the console/file output above,
+	// each receiving records at its own MinLevel (e.g. syslog, a TCP
+	// collector, or an in-memory ring buffer for a /debugz/logs handler).
+	//
+	// To replace the built-in console/file output with sinks entirely
+	// (e.g. a FileSink emitting JSON at DEBUG alongside a ConsoleSink
+	// emitting colored text at INFO) rather than adding to it, set
+	// DisableConsole and leave LogFile empty, then pass your own
+	// NewConsoleSink/NewFileSink here. Otherwise every sink receives the
+	// same records as the built-in output, which will double-write a
+	// ConsoleSink/FileSink pointed at the same destination.
+	Sinks []SinkRegistration
+
+	// DisableConsole turns off the built-in stdout writer. Combine with an
+	// empty LogFile and your own Sinks to replace the default single
+	// format/level console+file output rather than layering on top of it.
+	DisableConsole bool
+
+	// Sampling protects Sentry quota and disk IO from a single call site
+	// logging thousands of times per second during an incident.
+	Sampling SamplingConfig
+	// SentryRateLimit caps sustained sentry.CaptureMessage calls to this
+	// many events/sec, independent of Sampling. 0 means unlimited.
+	SentryRateLimit float64
+
+	// CrashDumpDir, if set, also drops Fatal stack traces and
+	// InstallCrashHandler dumps as a standalone file named with a
+	// timestamp and pid, for offline analysis.
+	CrashDumpDir string
 }
 
 // ConfigureLogging sets up standardized logging for the service
@@ -244,16 +402,32 @@ func ConfigureLoggingWithConfig(config LogConfig) *StandardLogger {
 	if config.BackupCount == 0 {
 		config.BackupCount = 5
 	}
+	if config.LogFormat == "" {
+		config.LogFormat = FormatText
+	}
 
 	var writer io.Writer = os.Stdout
+	if config.DisableConsole {
+		writer = io.Discard
+	}
 
 	// Setup file logging with rotation if log file is specified
 	var fileWriter *RotatingFileWriter
 	if config.LogFile != "" {
 		var err error
-		fileWriter, err = NewRotatingFileWriter(config.LogFile, config.MaxBytes, config.BackupCount)
+		fileWriter, err = NewRotatingFileWriterWithOptions(config.LogFile, RotationOptions{
+			MaxBytes:     config.MaxBytes,
+			BackupCount:  config.BackupCount,
+			Daily:        config.Daily,
+			MaxDays:      config.MaxDays,
+			MaxLines:     config.MaxLines,
+			RotateSuffix: config.RotateSuffix,
+			UTC:          config.RotateUTC,
+		})
 		if err != nil {
 			log.Printf("Failed to setup file logging: %v", err)
+		} else if config.DisableConsole {
+			writer = fileWriter
 		} else {
 			// Write to both console and file
 			writer = io.MultiWriter(os.Stdout, fileWriter)
@@ -265,17 +439,45 @@ func ConfigureLoggingWithConfig(config LogConfig) *StandardLogger {
 	}
 
 	defaultLogger = &StandardLogger{
-		serviceName: config.ServiceName,
-		logger:      log.New(writer, "", 0), // No flags, we format manually
-		fileWriter:  fileWriter,
+		serviceName:   config.ServiceName,
+		logger:        log.New(writer, "", 0), // No flags, we format manually
+		fileWriter:    fileWriter,
+		format:        config.LogFormat,
+		sinksMu:       &sync.RWMutex{},
+		sampling:      config.Sampling,
+		sentryLimiter: newTokenBucket(config.SentryRateLimit),
+		crashDumpDir:  config.CrashDumpDir,
+	}
+
+	for _, reg := range config.Sinks {
+		defaultLogger.AddSink(reg.Sink, reg.MinLevel)
+	}
+
+	if config.Async {
+		bufferSize := config.BufferSize
+		if bufferSize == 0 {
+			bufferSize = 1000
+		}
+		defaultLogger.startAsync(bufferSize, config.OverflowPolicy)
 	}
 
 	defaultLogger.Info("Standardized logging configured")
 	return defaultLogger
 }
 
-// Close closes the logger and any open file handles
+// Close closes the logger and any open file handles. If the logger is
+// running in async mode, it first drains the queue and stops the writer
+// goroutine so no buffered records are lost.
 func (l *StandardLogger) Close() error {
+	if l.async {
+		close(l.queue)
+		l.wg.Wait()
+		// The drain goroutine has already processed everything queued,
+		// including deferred sink writes; CloseSinks must not try to
+		// drain the now-closed queue itself.
+		l.async = false
+	}
+	l.CloseSinks()
 	if l.fileWriter != nil {
 		return l.fileWriter.Close()
 	}
@@ -290,109 +492,174 @@ func GetLogger() *StandardLogger {
 	return defaultLogger
 }
 
+// render formats a record according to the logger's configured LogFormat,
+// folding in any fields accumulated via With(). skip is forwarded to
+// runtime.Caller by the underlying formatter.
+func (l *StandardLogger) render(level LogLevel, message string, skip int) string {
+	if l.format == FormatJSON {
+		return formatLogJSON(level, message, l.serviceName, l.fields, skip)
+	}
+	return formatLogMessage(level, message+formatFieldsText(l.fields), skip)
+}
+
 // Debug logs a debug message
 func (l *StandardLogger) Debug(v ...interface{}) {
 	message := fmt.Sprintln(v...)
 	message = message[:len(message)-1] // Remove trailing newline
-	formatted := formatLogMessage(LevelDebug, message, 3)
-	l.logger.Print(formatted)
+	formatted := l.render(LevelDebug, message, 4)
+	l.write(formatted)
+	l.dispatchSinks(LevelDebug, message, l.fields, 4)
 }
 
 // Debugf logs a formatted debug message
 func (l *StandardLogger) Debugf(format string, v ...interface{}) {
 	message := fmt.Sprintf(format, v...)
-	formatted := formatLogMessage(LevelDebug, message, 3)
-	l.logger.Print(formatted)
+	formatted := l.render(LevelDebug, message, 4)
+	l.write(formatted)
+	l.dispatchSinks(LevelDebug, message, l.fields, 4)
 }
 
 // Info logs an info message
 func (l *StandardLogger) Info(v ...interface{}) {
 	message := fmt.Sprintln(v...)
 	message = message[:len(message)-1] // Remove trailing newline
-	formatted := formatLogMessage(LevelInfo, message, 3)
-	l.logger.Print(formatted)
+	formatted := l.render(LevelInfo, message, 4)
+	l.write(formatted)
+	l.dispatchSinks(LevelInfo, message, l.fields, 4)
 }
 
 // Infof logs a formatted info message
 func (l *StandardLogger) Infof(format string, v ...interface{}) {
 	message := fmt.Sprintf(format, v...)
-	formatted := formatLogMessage(LevelInfo, message, 3)
-	l.logger.Print(formatted)
+	formatted := l.render(LevelInfo, message, 4)
+	l.write(formatted)
+	l.dispatchSinks(LevelInfo, message, l.fields, 4)
 }
 
-// Warning logs a warning message
+// Warning logs a warning message. Under LogConfig.Sampling, repeated
+// warnings from the same call site are sampled rather than logged in full.
 func (l *StandardLogger) Warning(v ...interface{}) {
 	message := fmt.Sprintln(v...)
 	message = message[:len(message)-1] // Remove trailing newline
-	formatted := formatLogMessage(LevelWarning, message, 3)
-	l.logger.Print(formatted)
+	if !l.sampleAdmit(LevelWarning, 3) {
+		return
+	}
+	formatted := l.render(LevelWarning, message, 4)
+	l.write(formatted)
+	l.dispatchSinks(LevelWarning, message, l.fields, 4)
 
 	// Add to Sentry as breadcrumb
 	sentry.AddBreadcrumb(&sentry.Breadcrumb{
 		Message:  message,
 		Level:    sentry.LevelWarning,
 		Category: "log",
+		Data:     fieldsToSentryData(l.fields),
 	})
 }
 
-// Warningf logs a formatted warning message
+// Warningf logs a formatted warning message, subject to the same sampling as Warning.
 func (l *StandardLogger) Warningf(format string, v ...interface{}) {
 	message := fmt.Sprintf(format, v...)
-	formatted := formatLogMessage(LevelWarning, message, 3)
-	l.logger.Print(formatted)
+	if !l.sampleAdmit(LevelWarning, 3) {
+		return
+	}
+	formatted := l.render(LevelWarning, message, 4)
+	l.write(formatted)
+	l.dispatchSinks(LevelWarning, message, l.fields, 4)
 
 	// Add to Sentry as breadcrumb
 	sentry.AddBreadcrumb(&sentry.Breadcrumb{
 		Message:  message,
 		Level:    sentry.LevelWarning,
 		Category: "log",
+		Data:     fieldsToSentryData(l.fields),
 	})
 }
 
-// Error logs an error message and sends to Sentry
+// Error logs an error message and sends to Sentry, subject to
+// LogConfig.Sampling and LogConfig.SentryRateLimit.
 func (l *StandardLogger) Error(v ...interface{}) {
 	message := fmt.Sprintln(v...)
 	message = message[:len(message)-1] // Remove trailing newline
-	formatted := formatLogMessage(LevelError, message, 3)
-	l.logger.Print(formatted)
+	if !l.sampleAdmit(LevelError, 3) {
+		return
+	}
+	formatted := l.render(LevelError, message, 4)
+	l.write(formatted)
+	l.dispatchSinks(LevelError, message, l.fields, 4)
 
-	// Send to Sentry as error
-	sentry.CaptureMessage(message)
+	// Send to Sentry as error, with any With() fields as extras
+	l.captureSentry(message, l.fields)
 }
 
-// Errorf logs a formatted error message and sends to Sentry
+// Errorf logs a formatted error message and sends to Sentry, subject to the
+// same sampling and rate limiting as Error.
 func (l *StandardLogger) Errorf(format string, v ...interface{}) {
 	message := fmt.Sprintf(format, v...)
-	formatted := formatLogMessage(LevelError, message, 3)
-	l.logger.Print(formatted)
+	if !l.sampleAdmit(LevelError, 3) {
+		return
+	}
+	formatted := l.render(LevelError, message, 4)
+	l.write(formatted)
+	l.dispatchSinks(LevelError, message, l.fields, 4)
 
-	// Send to Sentry as error
-	sentry.CaptureMessage(message)
+	// Send to Sentry as error, with any With() fields as extras
+	l.captureSentry(message, l.fields)
 }
 
-// Fatal logs a fatal message, sends to Sentry, and exits
+// Fatal logs a fatal message with the current goroutine's stack trace, sends
+// to Sentry, and exits.
 func (l *StandardLogger) Fatal(v ...interface{}) {
 	message := fmt.Sprintln(v...)
 	message = message[:len(message)-1] // Remove trailing newline
-	formatted := formatLogMessage(LevelFatal, message, 3)
+	stack := captureStack(false)
+	withStack := message + "\n" + stack
+	formatted := l.render(LevelFatal, withStack, 4)
+	l.dispatchSinks(LevelFatal, withStack, l.fields, 4)
 
 	// Send to Sentry as fatal error
-	sentry.CaptureMessage(message)
+	captureSentryMessage(message, l.fields)
 	sentry.Flush(2 * time.Second)
 
+	// In async mode, drain everything queued ahead of this record so it
+	// isn't lost or printed out of order before we exit.
+	if l.async {
+		l.Flush(2 * time.Second)
+	}
+
+	if l.crashDumpDir != "" {
+		if err := writeCrashDumpFile(l.crashDumpDir, message, stack); err != nil {
+			log.Printf("Failed to write crash dump: %v", err)
+		}
+	}
+
 	// Log and exit
 	l.logger.Fatal(formatted)
 }
 
-// Fatalf logs a formatted fatal message, sends to Sentry, and exits
+// Fatalf logs a formatted fatal message with the current goroutine's stack
+// trace, sends to Sentry, and exits.
 func (l *StandardLogger) Fatalf(format string, v ...interface{}) {
 	message := fmt.Sprintf(format, v...)
-	formatted := formatLogMessage(LevelFatal, message, 3)
+	stack := captureStack(false)
+	withStack := message + "\n" + stack
+	formatted := l.render(LevelFatal, withStack, 4)
+	l.dispatchSinks(LevelFatal, withStack, l.fields, 4)
 
 	// Send to Sentry as fatal error
-	sentry.CaptureMessage(message)
+	captureSentryMessage(message, l.fields)
 	sentry.Flush(2 * time.Second)
 
+	if l.async {
+		l.Flush(2 * time.Second)
+	}
+
+	if l.crashDumpDir != "" {
+		if err := writeCrashDumpFile(l.crashDumpDir, message, stack); err != nil {
+			log.Printf("Failed to write crash dump: %v", err)
+		}
+	}
+
 	// Log and exit
 	l.logger.Fatal(formatted)
 }
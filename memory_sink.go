@@ -0,0 +1,53 @@
+package logging
+
+import "sync"
+
+// MemorySink retains the last N records in a ring buffer. It's meant for
+// exposing a /debugz/logs HTTP handler and for tests that want to assert on
+// what was logged without parsing console output.
+type MemorySink struct {
+	mu      sync.Mutex
+	records []Record
+	next    int
+	full    bool
+}
+
+// NewMemorySink creates a MemorySink holding up to capacity records.
+func NewMemorySink(capacity int) *MemorySink {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &MemorySink{records: make([]Record, capacity)}
+}
+
+func (s *MemorySink) Write(rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[s.next] = rec
+	s.next = (s.next + 1) % len(s.records)
+	if s.next == 0 {
+		s.full = true
+	}
+	return nil
+}
+
+func (s *MemorySink) Flush() error { return nil }
+func (s *MemorySink) Close() error { return nil }
+
+// Records returns the buffered records in chronological order.
+func (s *MemorySink) Records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.full {
+		out := make([]Record, s.next)
+		copy(out, s.records[:s.next])
+		return out
+	}
+
+	out := make([]Record, len(s.records))
+	copy(out, s.records[s.next:])
+	copy(out[len(s.records)-s.next:], s.records[:s.next])
+	return out
+}
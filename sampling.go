@@ -0,0 +1,164 @@
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// SamplingConfig protects Sentry quota and disk IO from a single hot call
+// site logging thousands of times per second during an incident. Within
+// each Tick window, the first N events are admitted in full; after that,
+// only 1 in M is. Suppressed events still bump a counter, summarized as a
+// single line by the first event from the same call site after their
+// window closes.
+type SamplingConfig struct {
+	Tick       time.Duration // Window size First/Thereafter apply over; default 1s.
+	First      int           // Always admit the first N events per window.
+	Thereafter int           // After First, admit 1 in Thereafter events. 0 disables sampling.
+}
+
+// sampleWindow tracks one call site's admission state for its current tick.
+type sampleWindow struct {
+	mu         sync.Mutex
+	end        time.Time
+	count      int64
+	suppressed int64
+	level      LogLevel
+	caller     string
+	tick       time.Duration
+}
+
+// sampleStates is keyed by the call site's program counter, same approach
+// as the V() vmodule cache.
+var sampleStates sync.Map // map[uintptr]*sampleWindow
+
+// sampleAdmit reports whether the event at the given skip depth should be
+// logged and sent to Sentry. When this is the first call from its call site
+// after a window with suppressed events has closed, it emits "suppressed N
+// duplicate LEVEL at file:line in last Ts" through l before returning; a
+// call site that stops logging emits no summary for its last window.
+func (l *StandardLogger) sampleAdmit(level LogLevel, skip int) bool {
+	if l.sampling.Thereafter <= 0 {
+		return true
+	}
+
+	pc, file, line, ok := runtime.Caller(skip)
+	caller := "unknown:0"
+	if ok {
+		caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+
+	v, _ := sampleStates.LoadOrStore(pc, &sampleWindow{})
+	w := v.(*sampleWindow)
+
+	tick := l.sampling.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	w.mu.Lock()
+	now := time.Now()
+	var summary string
+	var summaryLevel LogLevel
+	if now.After(w.end) {
+		if w.suppressed > 0 {
+			summary = fmt.Sprintf("suppressed %d duplicate %s at %s in last %s", w.suppressed, w.level, w.caller, w.tick)
+			summaryLevel = w.level
+		}
+		w.end = now.Add(tick)
+		w.count = 0
+		w.suppressed = 0
+		w.level = level
+		w.caller = caller
+		w.tick = tick
+	}
+
+	w.count++
+	admit := w.count <= int64(l.sampling.First)
+	if !admit {
+		if l.sampling.Thereafter <= 1 {
+			// "1 in 1" means no suppression beyond First: admit everything.
+			admit = true
+		} else {
+			offset := w.count - int64(l.sampling.First)
+			admit = offset%int64(l.sampling.Thereafter) == 1
+		}
+	}
+	if !admit {
+		w.suppressed++
+	}
+	w.mu.Unlock()
+
+	if summary != "" {
+		// render and dispatchSinks count skip so that 2 names the function
+		// calling them (here, sampleAdmit), whereas skip above counts from
+		// sampleAdmit itself as 0. Passing skip+2 to both tags the summary
+		// with the code that actually logged the sampled events instead of
+		// this call site.
+		l.write(l.render(summaryLevel, summary, skip+2))
+		l.dispatchSinks(summaryLevel, summary, l.fields, skip+2)
+	}
+	return admit
+}
+
+// tokenBucket is a simple events/sec limiter, used to cap the rate of
+// sentry.CaptureMessage calls independent of local log sampling.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens added per second
+	last     time.Time
+}
+
+// newTokenBucket returns a bucket that allows up to eventsPerSec sustained,
+// with a one-second burst capacity (at least one event). eventsPerSec <= 0
+// means unlimited.
+func newTokenBucket(eventsPerSec float64) *tokenBucket {
+	if eventsPerSec <= 0 {
+		return nil
+	}
+	capacity := eventsPerSec
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &tokenBucket{tokens: capacity, capacity: capacity, rate: eventsPerSec, last: time.Now()}
+}
+
+// Allow reports whether an event may proceed right now, consuming a token
+// if so. A nil bucket (unlimited) always allows.
+func (b *tokenBucket) Allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	b.tokens += elapsed * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// captureSentry sends msg to Sentry with fields as extras, subject to the
+// logger's SentryRateLimit. Rate-limited events are dropped silently (the
+// local log line has already been written).
+func (l *StandardLogger) captureSentry(msg string, fields []Field) {
+	if !l.sentryLimiter.Allow() {
+		return
+	}
+	captureSentryMessage(msg, fields)
+}
@@ -0,0 +1,192 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSampleAdmitFirstThenThereafter(t *testing.T) {
+	sampleStates = sync.Map{}
+
+	l := &StandardLogger{
+		logger:   log.New(io.Discard, "", 0),
+		sinksMu:  &sync.RWMutex{},
+		sampling: SamplingConfig{Tick: time.Hour, First: 2, Thereafter: 3},
+	}
+
+	var got []bool
+	for i := 0; i < 9; i++ {
+		got = append(got, l.sampleAdmit(LevelWarning, 1))
+	}
+
+	want := []bool{true, true, true, false, false, true, false, false, true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("call %d: admit = %v, want %v (full sequence: %v)", i+1, got[i], want[i], got)
+			break
+		}
+	}
+}
+
+func TestSampleAdmitDisabledWhenThereafterZero(t *testing.T) {
+	sampleStates = sync.Map{}
+
+	l := &StandardLogger{
+		logger:  log.New(io.Discard, "", 0),
+		sinksMu: &sync.RWMutex{},
+	}
+
+	for i := 0; i < 5; i++ {
+		if !l.sampleAdmit(LevelWarning, 1) {
+			t.Fatalf("call %d: sampleAdmit = false, want true (Thereafter: 0 disables sampling)", i+1)
+		}
+	}
+}
+
+func TestSampleAdmitSummaryOnWindowRollover(t *testing.T) {
+	sampleStates = sync.Map{}
+
+	var buf bytes.Buffer
+	l := &StandardLogger{
+		logger:   log.New(&buf, "", 0),
+		sinksMu:  &sync.RWMutex{},
+		format:   FormatText,
+		sampling: SamplingConfig{Tick: 20 * time.Millisecond, First: 1, Thereafter: 2},
+	}
+
+	// sampleAdmit keys its window by the caller's program counter, so every
+	// call in this test must come from the same call site; sleep in the
+	// loop rather than calling from a second line afterwards.
+	for i := 0; i < 5; i++ {
+		if i == 4 {
+			time.Sleep(30 * time.Millisecond) // let the window roll over
+		}
+		l.sampleAdmit(LevelWarning, 1) // the 5th call should flush the prior window's summary
+	}
+
+	if !strings.Contains(buf.String(), "suppressed") {
+		t.Errorf("buf = %q, want a suppressed-duplicates summary line", buf.String())
+	}
+}
+
+// TestSampleAdmitSummaryResolvesRealCaller reproduces a maintainer-filed
+// bug: the suppressed-summary record's bracketed caller tag used to resolve
+// to sampleAdmit's own call site inside sampling.go instead of the code
+// that actually logged the sampled events, because render/dispatchSinks
+// were called one frame deeper here than from a normal Warning/Error call.
+func TestSampleAdmitSummaryResolvesRealCaller(t *testing.T) {
+	sampleStates = sync.Map{}
+
+	var buf bytes.Buffer
+	l := &StandardLogger{
+		logger:   log.New(&buf, "", 0),
+		sinksMu:  &sync.RWMutex{},
+		format:   FormatText,
+		sampling: SamplingConfig{Tick: 20 * time.Millisecond, First: 1, Thereafter: 2},
+	}
+
+	for i := 0; i < 5; i++ {
+		if i == 4 {
+			time.Sleep(30 * time.Millisecond)
+		}
+		l.sampleAdmit(LevelWarning, 1)
+	}
+
+	if strings.Contains(buf.String(), "sampling.go") {
+		t.Errorf("summary caller tag resolved into sampling.go, want the real call site: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "sampling_test.go") {
+		t.Errorf("summary caller tag = %q, want it to name this test file", buf.String())
+	}
+}
+
+// TestSampleAdmitSummaryDispatchesToSinks reproduces a maintainer-filed bug:
+// the suppressed-summary record was only ever pushed through l.write, never
+// l.dispatchSinks, so a logger configured with DisableConsole and only
+// Sinks (as chunk0-5's own docs recommend) never received it at all.
+func TestSampleAdmitSummaryDispatchesToSinks(t *testing.T) {
+	sampleStates = sync.Map{}
+
+	mem := NewMemorySink(10)
+	l := &StandardLogger{
+		logger:   log.New(io.Discard, "", 0),
+		sinksMu:  &sync.RWMutex{},
+		format:   FormatText,
+		sampling: SamplingConfig{Tick: 20 * time.Millisecond, First: 1, Thereafter: 2},
+	}
+	l.AddSink(mem, LevelDebug)
+
+	for i := 0; i < 5; i++ {
+		if i == 4 {
+			time.Sleep(30 * time.Millisecond)
+		}
+		l.sampleAdmit(LevelWarning, 1)
+	}
+
+	var summary *Record
+	for i, rec := range mem.Records() {
+		if strings.Contains(rec.Message, "suppressed") {
+			summary = &mem.Records()[i]
+		}
+	}
+	if summary == nil {
+		t.Fatalf("memory sink records = %v, want a suppressed-duplicates summary among them", mem.Records())
+	}
+	if strings.Contains(summary.Caller, "sampling.go") || !strings.Contains(summary.Caller, "sampling_test.go") {
+		t.Errorf("summary record Caller = %q, want it to name this test file, not sampling.go", summary.Caller)
+	}
+}
+
+// TestSampleAdmitThereafterOneAdmitsAll reproduces a maintainer-filed bug:
+// Thereafter == 1 ("sample 1 in 1", i.e. no suppression beyond First) used
+// to suppress every event forever instead of admitting all of them, since
+// offset % 1 is always 0 and never equals the admit check's 1.
+func TestSampleAdmitThereafterOneAdmitsAll(t *testing.T) {
+	sampleStates = sync.Map{}
+
+	l := &StandardLogger{
+		logger:   log.New(io.Discard, "", 0),
+		sinksMu:  &sync.RWMutex{},
+		sampling: SamplingConfig{Tick: time.Hour, First: 1, Thereafter: 1},
+	}
+
+	for i := 0; i < 5; i++ {
+		if !l.sampleAdmit(LevelWarning, 1) {
+			t.Fatalf("call %d: sampleAdmit = false, want true (Thereafter: 1 should admit everything)", i+1)
+		}
+	}
+}
+
+func TestTokenBucketAllow(t *testing.T) {
+	b := newTokenBucket(2) // 2 events/sec, burst capacity 2
+
+	if !b.Allow() {
+		t.Fatal("expected first token to be allowed")
+	}
+	if !b.Allow() {
+		t.Fatal("expected second token (within burst capacity) to be allowed")
+	}
+	if b.Allow() {
+		t.Fatal("expected third immediate call to be rate-limited")
+	}
+
+	time.Sleep(600 * time.Millisecond) // refill ~1.2 tokens at 2/sec
+	if !b.Allow() {
+		t.Fatal("expected a token to be available after waiting for refill")
+	}
+}
+
+func TestNewTokenBucketUnlimited(t *testing.T) {
+	if b := newTokenBucket(0); b != nil {
+		t.Fatalf("newTokenBucket(0) = %v, want nil (unlimited)", b)
+	}
+	var nilBucket *tokenBucket
+	if !nilBucket.Allow() {
+		t.Fatal("nil tokenBucket.Allow() should always return true")
+	}
+}
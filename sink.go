@@ -0,0 +1,243 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// Record is the structured representation of a single log line, handed to
+// every registered Sink regardless of that sink's own rendering format.
+type Record struct {
+	Time    time.Time
+	Level   LogLevel
+	Caller  string
+	Message string
+	Service string
+	Fields  []Field
+}
+
+// Sink receives log records in addition to a StandardLogger's built-in
+// console/file output. Register one with AddSink to ship logs to syslog,
+// a TCP/UDP collector, an in-memory ring buffer for a /debugz/logs handler,
+// and so on. Flush and Close should be safe to call even if nothing was
+// ever written.
+type Sink interface {
+	Write(record Record) error
+	Flush() error
+	Close() error
+}
+
+// sinkBinding pairs a Sink with the minimum level it should receive, so a
+// single process can e.g. ship DEBUG to a file sink while only ERROR and
+// above reaches a syslog sink.
+type sinkBinding struct {
+	sink     Sink
+	minLevel LogLevel
+}
+
+// levelRank orders levels for minLevel comparisons.
+var levelRank = map[LogLevel]int{
+	LevelDebug:    0,
+	LevelInfo:     1,
+	LevelWarning:  2,
+	LevelError:    3,
+	LevelCritical: 4,
+	LevelFatal:    5,
+}
+
+// AddSink registers an additional sink that receives every record at
+// minLevel or above, alongside the logger's built-in console/file output.
+func (l *StandardLogger) AddSink(sink Sink, minLevel LogLevel) {
+	l.sinksMu.Lock()
+	defer l.sinksMu.Unlock()
+	l.sinks = append(l.sinks, sinkBinding{sink: sink, minLevel: minLevel})
+}
+
+// dispatchSinks resolves the caller location at skip and fans the record
+// out to every registered sink whose minLevel admits it. skip counts frames
+// the same way render's does, so callers pass both the same value and sinks
+// see the same caller as the console/file output. The caller is
+// resolved here, on the logging goroutine, since it wouldn't otherwise be
+// meaningful once deferred onto the async drain goroutine below.
+func (l *StandardLogger) dispatchSinks(level LogLevel, message string, fields []Field, skip int) {
+	l.sinksMu.RLock()
+	sinks := l.sinks
+	l.sinksMu.RUnlock()
+	if len(sinks) == 0 {
+		return
+	}
+
+	// render reaches runtime.Caller through one more frame
+	// (formatLogMessage/formatLogJSON) than this function does.
+	_, file, line, ok := runtime.Caller(skip - 1)
+	caller := "unknown:0"
+	if ok {
+		caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+
+	rec := Record{
+		Time:    time.Now(),
+		Level:   level,
+		Caller:  caller,
+		Message: message,
+		Service: l.serviceName,
+		Fields:  fields,
+	}
+
+	// Sinks like SyslogSink/SocketSink do blocking network IO; in async mode
+	// that write is deferred onto the same drain goroutine as console/file
+	// output instead of running on the caller's goroutine.
+	if l.async {
+		l.enqueue(logItem{fn: func() { l.writeToSinks(rec, sinks) }})
+		return
+	}
+	l.writeToSinks(rec, sinks)
+}
+
+// writeToSinks fans rec out to every sink in sinks whose minLevel admits it.
+func (l *StandardLogger) writeToSinks(rec Record, sinks []sinkBinding) {
+	for _, b := range sinks {
+		if levelRank[rec.Level] < levelRank[b.minLevel] {
+			continue
+		}
+		if err := b.sink.Write(rec); err != nil {
+			log.Printf("logging: sink write failed: %v", err)
+		}
+	}
+}
+
+// drainAsync blocks until every item enqueued so far, including a deferred
+// dispatchSinks write, has been handled by the drain goroutine. It is a
+// no-op when the logger isn't running in async mode.
+func (l *StandardLogger) drainAsync() {
+	if !l.async {
+		return
+	}
+	ack := make(chan struct{})
+	l.queue <- logItem{ack: ack}
+	<-ack
+}
+
+// FlushSinks flushes every registered sink. In async mode this first waits
+// for any sink writes already queued by dispatchSinks to be written, so a
+// Flush immediately after a log call doesn't race the deferred write.
+func (l *StandardLogger) FlushSinks() {
+	l.drainAsync()
+	l.sinksMu.RLock()
+	defer l.sinksMu.RUnlock()
+	for _, b := range l.sinks {
+		b.sink.Flush()
+	}
+}
+
+// CloseSinks closes every registered sink. In async mode this first waits
+// for any sink writes already queued by dispatchSinks to be written, so a
+// sink isn't closed out from under a write still in flight on the drain
+// goroutine.
+func (l *StandardLogger) CloseSinks() {
+	l.drainAsync()
+	l.sinksMu.RLock()
+	defer l.sinksMu.RUnlock()
+	for _, b := range l.sinks {
+		b.sink.Close()
+	}
+}
+
+// SinkRegistration is a sink plus its minimum level, as passed to
+// LogConfig.Sinks so additional sinks are wired up at construction time.
+type SinkRegistration struct {
+	Sink     Sink
+	MinLevel LogLevel
+}
+
+// ConsoleSink writes records (colored when stdout is a terminal) to an
+// io.Writer in either text or JSON form. It is the Sink equivalent of the
+// logger's built-in stdout output.
+type ConsoleSink struct {
+	out    io.Writer
+	format LogFormat
+}
+
+// NewConsoleSink returns a Sink that renders records to out.
+func NewConsoleSink(out io.Writer, format LogFormat) *ConsoleSink {
+	if format == "" {
+		format = FormatText
+	}
+	return &ConsoleSink{out: out, format: format}
+}
+
+func (s *ConsoleSink) Write(rec Record) error {
+	_, err := fmt.Fprintln(s.out, renderRecord(rec, s.format))
+	return err
+}
+
+func (s *ConsoleSink) Flush() error { return nil }
+func (s *ConsoleSink) Close() error { return nil }
+
+// FileSink writes records to a RotatingFileWriter in either text or JSON
+// form, independent of the console's format.
+type FileSink struct {
+	file   *RotatingFileWriter
+	format LogFormat
+}
+
+// NewFileSink returns a Sink backed by an already-open RotatingFileWriter.
+func NewFileSink(file *RotatingFileWriter, format LogFormat) *FileSink {
+	if format == "" {
+		format = FormatText
+	}
+	return &FileSink{file: file, format: format}
+}
+
+func (s *FileSink) Write(rec Record) error {
+	_, err := fmt.Fprintln(s.file, renderRecord(rec, s.format))
+	return err
+}
+
+func (s *FileSink) Flush() error { return nil }
+func (s *FileSink) Close() error { return s.file.Close() }
+
+// renderRecord formats a Record the same way the logger's own
+// formatLogMessage/formatLogJSON do, but from an already-resolved caller
+// string rather than walking the stack again.
+func renderRecord(rec Record, format LogFormat) string {
+	message := rec.Message + formatFieldsText(rec.Fields)
+	if format == FormatJSON {
+		var fieldMap map[string]interface{}
+		if len(rec.Fields) > 0 {
+			fieldMap = make(map[string]interface{}, len(rec.Fields))
+			for _, f := range rec.Fields {
+				fieldMap[f.Key] = f.Value
+			}
+		}
+		data, err := json.Marshal(jsonRecord{
+			Timestamp: rec.Time.UTC().Format(time.RFC3339Nano),
+			Level:     string(rec.Level),
+			Caller:    rec.Caller,
+			Message:   rec.Message,
+			Service:   rec.Service,
+			Fields:    fieldMap,
+		})
+		if err != nil {
+			return fmt.Sprintf("failed to marshal log record: %v", err)
+		}
+		return string(data)
+	}
+
+	timestamp := fmt.Sprintf("[%04d-%02d-%02d %02d:%02d:%02d,%03d]",
+		rec.Time.Year(), rec.Time.Month(), rec.Time.Day(),
+		rec.Time.Hour(), rec.Time.Minute(), rec.Time.Second(),
+		rec.Time.Nanosecond()/1000000)
+
+	levelStr := string(rec.Level)
+	if color := getColor(rec.Level); color != "" {
+		levelStr = color + levelStr + ColorReset
+	}
+	return fmt.Sprintf("%s [%s] [%s] %s", timestamp, levelStr, rec.Caller, message)
+}
+
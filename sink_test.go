@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingSink simulates a sink that does slow blocking IO, like SyslogSink
+// or SocketSink talking to a remote collector.
+type blockingSink struct {
+	delay    time.Duration
+	received chan Record
+}
+
+func (s *blockingSink) Write(rec Record) error {
+	time.Sleep(s.delay)
+	s.received <- rec
+	return nil
+}
+
+func (s *blockingSink) Flush() error { return nil }
+func (s *blockingSink) Close() error { return nil }
+
+// TestDispatchSinksAsyncDoesNotBlockCaller reproduces a maintainer-filed bug:
+// dispatchSinks used to run every sink Write synchronously on the calling
+// goroutine even when the logger was in Async mode, defeating the point of
+// chunk0-4 for any service that also registers a slow sink.
+func TestDispatchSinksAsyncDoesNotBlockCaller(t *testing.T) {
+	l := &StandardLogger{
+		logger:  log.New(io.Discard, "", 0),
+		sinksMu: &sync.RWMutex{},
+	}
+	l.startAsync(10, Block)
+	defer l.Close()
+
+	sink := &blockingSink{delay: 100 * time.Millisecond, received: make(chan Record, 1)}
+	l.AddSink(sink, LevelDebug)
+
+	start := time.Now()
+	l.dispatchSinks(LevelInfo, "hello", nil, 1)
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("dispatchSinks blocked the caller for %v in async mode; sink writes should run on the drain goroutine", elapsed)
+	}
+
+	select {
+	case <-sink.received:
+	case <-time.After(time.Second):
+		t.Fatal("sink never received the record")
+	}
+}
+
+// TestFlushSinksWaitsForQueuedDispatch reproduces a maintainer-filed bug:
+// FlushSinks/CloseSinks used to act on l.sinks immediately, racing a sink
+// write that dispatchSinks had only just enqueued onto the async drain
+// goroutine rather than performed inline.
+func TestFlushSinksWaitsForQueuedDispatch(t *testing.T) {
+	l := &StandardLogger{
+		logger:  log.New(io.Discard, "", 0),
+		sinksMu: &sync.RWMutex{},
+	}
+	l.startAsync(10, Block)
+	defer l.Close()
+
+	sink := &blockingSink{delay: 20 * time.Millisecond, received: make(chan Record, 1)}
+	l.AddSink(sink, LevelDebug)
+
+	l.dispatchSinks(LevelInfo, "hello", nil, 1)
+	l.FlushSinks()
+
+	select {
+	case <-sink.received:
+	default:
+		t.Fatal("FlushSinks returned before the queued sink write ran")
+	}
+}
+
+func TestMemorySinkRingBufferWraps(t *testing.T) {
+	mem := NewMemorySink(3)
+	for i := 0; i < 5; i++ {
+		mem.Write(Record{Message: string(rune('a' + i))})
+	}
+
+	records := mem.Records()
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3 (capacity)", len(records))
+	}
+	want := []string{"c", "d", "e"}
+	for i, rec := range records {
+		if rec.Message != want[i] {
+			t.Errorf("records[%d].Message = %q, want %q (expected oldest to have been evicted)", i, rec.Message, want[i])
+		}
+	}
+}
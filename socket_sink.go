@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// SocketSink ships newline-delimited JSON records to a TCP or UDP endpoint,
+// reconnecting lazily on the next write after a failure rather than
+// blocking the caller.
+type SocketSink struct {
+	network string // "tcp" or "udp"
+	address string
+	timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSocketSink returns a Sink that dials network/address on first use.
+// network is "tcp" or "udp"; dialTimeout bounds both the initial dial and
+// each write.
+func NewSocketSink(network, address string, dialTimeout time.Duration) *SocketSink {
+	if dialTimeout <= 0 {
+		dialTimeout = 5 * time.Second
+	}
+	return &SocketSink{network: network, address: address, timeout: dialTimeout}
+}
+
+func (s *SocketSink) Write(rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.address, s.timeout)
+		if err != nil {
+			return fmt.Errorf("socket sink: dial %s: %w", s.address, err)
+		}
+		s.conn = conn
+	}
+
+	var fieldMap map[string]interface{}
+	if len(rec.Fields) > 0 {
+		fieldMap = make(map[string]interface{}, len(rec.Fields))
+		for _, f := range rec.Fields {
+			fieldMap[f.Key] = f.Value
+		}
+	}
+
+	data, err := json.Marshal(jsonRecord{
+		Timestamp: rec.Time.UTC().Format(time.RFC3339Nano),
+		Level:     string(rec.Level),
+		Caller:    rec.Caller,
+		Message:   rec.Message,
+		Service:   rec.Service,
+		Fields:    fieldMap,
+	})
+	if err != nil {
+		return fmt.Errorf("socket sink: marshal record: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	if _, err := s.conn.Write(data); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("socket sink: write to %s: %w", s.address, err)
+	}
+	return nil
+}
+
+func (s *SocketSink) Flush() error { return nil }
+
+func (s *SocketSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
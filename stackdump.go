@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// captureStack returns a stack trace from runtime.Stack, growing the buffer
+// until the dump fits entirely. all selects every goroutine instead of just
+// the caller's.
+func captureStack(all bool) string {
+	buf := make([]byte, 16*1024)
+	for {
+		n := runtime.Stack(buf, all)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// writeCrashDumpFile drops label and stack into dir as a standalone file
+// named with the current timestamp and pid, for offline analysis separate
+// from the regular log output.
+func writeCrashDumpFile(dir, label, stack string) error {
+	name := fmt.Sprintf("crash-%s-%d.log", time.Now().Format("20060102-150405"), os.Getpid())
+	content := label + "\n" + stack
+	return os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+}
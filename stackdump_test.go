@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCaptureStackContainsCurrentGoroutine(t *testing.T) {
+	stack := captureStack(false)
+	if !strings.Contains(stack, "goroutine") {
+		t.Errorf("captureStack(false) = %q, want it to contain a goroutine header", stack)
+	}
+	if !strings.Contains(stack, "TestCaptureStackContainsCurrentGoroutine") {
+		t.Errorf("captureStack(false) did not mention this test's own frame: %q", stack)
+	}
+}
+
+func TestCaptureStackAllGoroutines(t *testing.T) {
+	stack := captureStack(true)
+	if strings.Count(stack, "goroutine ") < 2 {
+		t.Errorf("captureStack(true) should dump more than one goroutine when the runtime has others; got %q", stack)
+	}
+}
+
+func TestWriteCrashDumpFile(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := writeCrashDumpFile(dir, "fatal signal received: segmentation violation", "goroutine 1 [running]:\n...\n"); err != nil {
+		t.Fatalf("writeCrashDumpFile: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d files in crash dump dir, want 1: %v", len(entries), entries)
+	}
+
+	name := entries[0].Name()
+	if !strings.HasPrefix(name, "crash-") || !strings.Contains(name, strconv.Itoa(os.Getpid())) {
+		t.Errorf("crash dump filename = %q, want a crash-<timestamp>-<pid>.log pattern", name)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(content), "fatal signal received") || !strings.Contains(string(content), "goroutine 1") {
+		t.Errorf("crash dump content = %q, want both the label and stack", string(content))
+	}
+}
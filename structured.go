@@ -0,0 +1,186 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// Field is a single structured key-value pair attached to a log record.
+// Construct one directly, or build a batch from alternating keys/values
+// with the *w methods (Infow, Errorw, ...).
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F is a convenience constructor for a Field.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// With returns a child logger that carries fields into every subsequent
+// record in addition to the ones already attached to l. Typical use is to
+// attach request-scoped context once (request_id, user_id, trace_id) and
+// pass the child logger down the call stack.
+func (l *StandardLogger) With(fields ...Field) *StandardLogger {
+	merged := make([]Field, 0, len(l.fields)+len(fields))
+	merged = append(merged, l.fields...)
+	merged = append(merged, fields...)
+
+	return &StandardLogger{
+		serviceName:    l.serviceName,
+		logger:         l.logger,
+		fileWriter:     l.fileWriter,
+		format:         l.format,
+		fields:         merged,
+		async:          l.async,
+		overflowPolicy: l.overflowPolicy,
+		queue:          l.queue,
+		wg:             l.wg,
+		sinksMu:        l.sinksMu,
+		sinks:          l.sinks,
+		sampling:       l.sampling,
+		sentryLimiter:  l.sentryLimiter,
+		crashDumpDir:   l.crashDumpDir,
+	}
+}
+
+// fieldsFromKV converts a flat keysAndValues slice (as accepted by Infow,
+// Errorw, etc.) into Fields. A key with no matching value is dropped.
+func fieldsFromKV(keysAndValues ...interface{}) []Field {
+	fields := make([]Field, 0, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", keysAndValues[i])
+		}
+		fields = append(fields, Field{Key: key, Value: keysAndValues[i+1]})
+	}
+	return fields
+}
+
+// formatFieldsText renders fields as " key=value key2=value2" for the text
+// format, so JSON and bracketed output carry the same information.
+func formatFieldsText(fields []Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		fmt.Fprintf(&b, "%v", f.Value)
+	}
+	return b.String()
+}
+
+// fieldsToSentryData converts Fields into the map shape sentry.Breadcrumb.Data expects.
+func fieldsToSentryData(fields []Field) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	data := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		data[f.Key] = f.Value
+	}
+	return data
+}
+
+// captureSentryMessage sends msg to Sentry, attaching fields as extras
+// instead of flattening them into the message string.
+func captureSentryMessage(msg string, fields []Field) {
+	if len(fields) == 0 {
+		sentry.CaptureMessage(msg)
+		return
+	}
+	sentry.WithScope(func(scope *sentry.Scope) {
+		for _, f := range fields {
+			scope.SetExtra(f.Key, f.Value)
+		}
+		sentry.CaptureMessage(msg)
+	})
+}
+
+// Debugw logs a debug message with structured key-value fields.
+func (l *StandardLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	fields := append(append([]Field{}, l.fields...), fieldsFromKV(keysAndValues...)...)
+	l.write(l.renderFields(LevelDebug, msg, fields, 4))
+	l.dispatchSinks(LevelDebug, msg, fields, 4)
+}
+
+// Infow logs an info message with structured key-value fields.
+func (l *StandardLogger) Infow(msg string, keysAndValues ...interface{}) {
+	fields := append(append([]Field{}, l.fields...), fieldsFromKV(keysAndValues...)...)
+	l.write(l.renderFields(LevelInfo, msg, fields, 4))
+	l.dispatchSinks(LevelInfo, msg, fields, 4)
+}
+
+// Warningw logs a warning message with structured key-value fields and
+// records it as a Sentry breadcrumb with the fields attached as data.
+// Subject to LogConfig.Sampling like Warning.
+func (l *StandardLogger) Warningw(msg string, keysAndValues ...interface{}) {
+	fields := append(append([]Field{}, l.fields...), fieldsFromKV(keysAndValues...)...)
+	if !l.sampleAdmit(LevelWarning, 3) {
+		return
+	}
+	l.write(l.renderFields(LevelWarning, msg, fields, 4))
+	l.dispatchSinks(LevelWarning, msg, fields, 4)
+
+	sentry.AddBreadcrumb(&sentry.Breadcrumb{
+		Message:  msg,
+		Level:    sentry.LevelWarning,
+		Category: "log",
+		Data:     fieldsToSentryData(fields),
+	})
+}
+
+// Errorw logs an error message with structured key-value fields and sends
+// it to Sentry with the fields attached as extras, subject to
+// LogConfig.Sampling and LogConfig.SentryRateLimit like Error.
+func (l *StandardLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	fields := append(append([]Field{}, l.fields...), fieldsFromKV(keysAndValues...)...)
+	if !l.sampleAdmit(LevelError, 3) {
+		return
+	}
+	l.write(l.renderFields(LevelError, msg, fields, 4))
+	l.dispatchSinks(LevelError, msg, fields, 4)
+
+	l.captureSentry(msg, fields)
+}
+
+// renderFields is like render but takes an explicit field set, used by the
+// *w methods which combine inherited and call-site fields themselves.
+func (l *StandardLogger) renderFields(level LogLevel, message string, fields []Field, skip int) string {
+	if l.format == FormatJSON {
+		return formatLogJSON(level, message, l.serviceName, fields, skip)
+	}
+	return formatLogMessage(level, message+formatFieldsText(fields), skip)
+}
+
+// Debugw logs a debug message with structured fields using the default logger.
+func Debugw(msg string, keysAndValues ...interface{}) {
+	GetLogger().Debugw(msg, keysAndValues...)
+}
+
+// Infow logs an info message with structured fields using the default logger.
+func Infow(msg string, keysAndValues ...interface{}) {
+	GetLogger().Infow(msg, keysAndValues...)
+}
+
+// Warningw logs a warning message with structured fields using the default logger.
+func Warningw(msg string, keysAndValues ...interface{}) {
+	GetLogger().Warningw(msg, keysAndValues...)
+}
+
+// Errorw logs an error message with structured fields using the default logger.
+func Errorw(msg string, keysAndValues ...interface{}) {
+	GetLogger().Errorw(msg, keysAndValues...)
+}
+
+// With returns a child of the default logger carrying the given fields.
+func With(fields ...Field) *StandardLogger {
+	return GetLogger().With(fields...)
+}
@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+	"testing"
+)
+
+func TestWithInheritsAndAppendsFields(t *testing.T) {
+	base := &StandardLogger{
+		logger:  log.New(io.Discard, "", 0),
+		sinksMu: &sync.RWMutex{},
+		fields:  []Field{F("service", "auth")},
+	}
+
+	child := base.With(F("request_id", "abc-123"))
+
+	if len(base.fields) != 1 {
+		t.Errorf("base.fields mutated by With: %v", base.fields)
+	}
+
+	want := []Field{{Key: "service", Value: "auth"}, {Key: "request_id", Value: "abc-123"}}
+	if len(child.fields) != len(want) {
+		t.Fatalf("child.fields = %v, want %v", child.fields, want)
+	}
+	for i := range want {
+		if child.fields[i] != want[i] {
+			t.Errorf("child.fields[%d] = %v, want %v", i, child.fields[i], want[i])
+		}
+	}
+}
+
+func TestWithOverrideKeepsBothEntries(t *testing.T) {
+	// With doesn't dedupe by key -- a later field with the same key as an
+	// earlier one is appended, not merged. formatFieldsText prints both
+	// entries; JSON encoding (map-based) surfaces only the last value for a
+	// duplicate key.
+	base := &StandardLogger{
+		logger:  log.New(io.Discard, "", 0),
+		sinksMu: &sync.RWMutex{},
+		fields:  []Field{F("env", "staging")},
+	}
+
+	child := base.With(F("env", "prod"))
+
+	if len(child.fields) != 2 {
+		t.Fatalf("child.fields = %v, want both the inherited and overriding env fields present", child.fields)
+	}
+	if child.fields[0].Value != "staging" || child.fields[1].Value != "prod" {
+		t.Errorf("child.fields = %v, want [staging, prod] in append order", child.fields)
+	}
+}
+
+func TestFieldsFromKVTrailingUnmatchedKeyDropped(t *testing.T) {
+	fields := fieldsFromKV("a", 1, "b", 2, "dangling")
+
+	want := []Field{{Key: "a", Value: 1}, {Key: "b", Value: 2}}
+	if len(fields) != len(want) {
+		t.Fatalf("fieldsFromKV = %v, want %v (trailing unmatched key dropped)", fields, want)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("fields[%d] = %v, want %v", i, fields[i], want[i])
+		}
+	}
+}
+
+func TestFieldsFromKVNonStringKeyStringified(t *testing.T) {
+	fields := fieldsFromKV(42, "answer")
+
+	if len(fields) != 1 {
+		t.Fatalf("fieldsFromKV = %v, want 1 field", fields)
+	}
+	if fields[0].Key != "42" || fields[0].Value != "answer" {
+		t.Errorf("fields[0] = %+v, want Key=\"42\" Value=\"answer\"", fields[0])
+	}
+}
+
+func TestInfowJSONShape(t *testing.T) {
+	var buf bytes.Buffer
+	l := &StandardLogger{
+		logger:  log.New(&buf, "", 0),
+		sinksMu: &sync.RWMutex{},
+		format:  FormatJSON,
+		fields:  []Field{F("service", "auth")},
+	}
+
+	l.Infow("user signed in", "user_id", "u-1")
+
+	var rec jsonRecord
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("Infow output isn't valid JSON: %v\noutput: %s", err, buf.String())
+	}
+
+	if rec.Level != string(LevelInfo) {
+		t.Errorf("rec.Level = %q, want %q", rec.Level, LevelInfo)
+	}
+	if rec.Message != "user signed in" {
+		t.Errorf("rec.Message = %q, want %q", rec.Message, "user signed in")
+	}
+	if rec.Fields["service"] != "auth" {
+		t.Errorf("rec.Fields[service] = %v, want the inherited field to be present", rec.Fields["service"])
+	}
+	if rec.Fields["user_id"] != "u-1" {
+		t.Errorf("rec.Fields[user_id] = %v, want the call-site field to be present", rec.Fields["user_id"])
+	}
+}
@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogSinkWritesRFC5424Framing(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+
+	lineCh := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lineCh <- line
+	}()
+
+	sink, err := NewSyslogSink("tcp", ln.Addr().String(), "myapp", 0)
+	if err != nil {
+		t.Fatalf("NewSyslogSink: %v", err)
+	}
+	defer sink.Close()
+
+	if err := sink.Write(Record{Level: LevelError, Message: "disk full"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	select {
+	case line := <-lineCh:
+		// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+		if !strings.HasPrefix(line, "<11>1 ") {
+			t.Errorf("line = %q, want RFC 5424 PRI <11>1 (user.err) prefix", line)
+		}
+		if !strings.Contains(line, " myapp ") {
+			t.Errorf("line = %q, want APP-NAME %q present", line, "myapp")
+		}
+		if !strings.Contains(line, "disk full") {
+			t.Errorf("line = %q, want the message present", line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("syslog collector never received a line")
+	}
+}
+
+// TestSyslogSinkEscapesEmbeddedNewlines guards against a multi-line message
+// (e.g. a crash stack dump) being split into bogus headerless frames by a
+// receiver that frames on newlines, as this sink's own wire format does.
+func TestSyslogSinkEscapesEmbeddedNewlines(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+
+	lineCh := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lineCh <- line
+	}()
+
+	sink, err := NewSyslogSink("tcp", ln.Addr().String(), "myapp", 0)
+	if err != nil {
+		t.Fatalf("NewSyslogSink: %v", err)
+	}
+	defer sink.Close()
+
+	if err := sink.Write(Record{Level: LevelFatal, Message: "fatal signal\nstack trace line 1\nstack trace line 2"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	select {
+	case line := <-lineCh:
+		if strings.Count(line, "\n") != 1 {
+			t.Errorf("line = %q, want exactly one trailing newline (embedded newlines should be escaped)", line)
+		}
+		if !strings.Contains(line, "fatal signal\\nstack trace line 1\\nstack trace line 2") {
+			t.Errorf("line = %q, want embedded newlines escaped as literal \\n", line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("syslog collector never received a line")
+	}
+}
+
+func TestSyslogSeverityMapping(t *testing.T) {
+	cases := map[LogLevel]int{
+		LevelDebug:    syslogSeverityDebug,
+		LevelInfo:     syslogSeverityInfo,
+		LevelWarning:  syslogSeverityWarning,
+		LevelError:    syslogSeverityErr,
+		LevelCritical: syslogSeverityCrit,
+		LevelFatal:    syslogSeverityEmerg,
+	}
+	for level, want := range cases {
+		if got := syslogSeverityFor(level); got != want {
+			t.Errorf("syslogSeverityFor(%v) = %d, want %d", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Verbose is a bool-like value returned by V(level). Its Info/Infof methods
+// are no-ops when the gate is closed, so callers at suppressed call sites
+// pay only the cost of the atomic load and vmodule lookup in V(). It carries
+// the logger it was created from so a gated call on a logger built via
+// With() or a non-default ConfigureLoggingWithConfig still logs through
+// that logger rather than always falling back to the package default.
+type Verbose struct {
+	enabled bool
+	logger  *StandardLogger
+}
+
+// globalVerbosity is the verbosity threshold used when no -vmodule override
+// matches the caller's file. Set via SetVerbosity or SURREALITY_LOG_V.
+var globalVerbosity int32
+
+// vmoduleRules holds the parsed "-vmodule" style overrides in the order they
+// were given; when several match a file, the last one wins.
+var (
+	vmoduleMu    sync.RWMutex
+	vmoduleRules []vmoduleRule
+)
+
+// vPCCache memoizes the resolved verbosity level for a given call-site
+// program counter, so hot paths only glob-match once per call site. Entries
+// are tagged with vPCCacheEpoch rather than the map being swapped out on
+// SetVerbosity/SetVModule, since reassigning vPCCache out from under
+// concurrent Load/Store calls in resolveVerbosity would be a data race.
+var vPCCache sync.Map // map[uintptr]vCacheEntry
+
+// vPCCacheEpoch is bumped by SetVerbosity/SetVModule to invalidate vPCCache
+// without touching the map itself.
+var vPCCacheEpoch int32
+
+// vCacheEntry is the value type stored in vPCCache.
+type vCacheEntry struct {
+	epoch int32
+	level int32
+}
+
+type vmoduleRule struct {
+	pattern string
+	level   int32
+}
+
+func init() {
+	if v := os.Getenv("SURREALITY_LOG_V"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			atomic.StoreInt32(&globalVerbosity, int32(n))
+		}
+	}
+	if vm := os.Getenv("SURREALITY_LOG_VMODULE"); vm != "" {
+		SetVModule(vm)
+	}
+}
+
+// SetVerbosity sets the global verbosity threshold used when a call site's
+// file doesn't match any -vmodule override.
+func SetVerbosity(level int) {
+	atomic.StoreInt32(&globalVerbosity, int32(level))
+	atomic.AddInt32(&vPCCacheEpoch, 1)
+}
+
+// SetVModule parses a glog/klog-style "-vmodule" override string, e.g.
+// "auth.go=3,db/*=2", and replaces the current override set. Patterns are
+// matched against the base filename of the call site resolved via
+// runtime.Caller, or, for a pattern with a directory component, against as
+// many trailing path components as the pattern has.
+func SetVModule(spec string) {
+	var rules []vmoduleRule
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		level, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil {
+			continue
+		}
+		rules = append(rules, vmoduleRule{pattern: strings.TrimSpace(kv[0]), level: int32(level)})
+	}
+
+	vmoduleMu.Lock()
+	vmoduleRules = rules
+	vmoduleMu.Unlock()
+	atomic.AddInt32(&vPCCacheEpoch, 1)
+}
+
+// vmoduleLevelForFile returns the verbosity level for file according to the
+// current -vmodule rules, and whether any rule matched.
+func vmoduleLevelForFile(file string) (int32, bool) {
+	vmoduleMu.RLock()
+	defer vmoduleMu.RUnlock()
+
+	base := filepath.Base(file)
+	var (
+		level   int32
+		matched bool
+	)
+	for _, rule := range vmoduleRules {
+		pattern := rule.pattern
+		candidate := base
+		if strings.Contains(pattern, "/") {
+			// e.g. "db/*" is matched against "db/conn.go" of ".../db/conn.go".
+			parts := strings.Split(filepath.ToSlash(file), "/")
+			if n := strings.Count(pattern, "/") + 1; len(parts) > n {
+				parts = parts[len(parts)-n:]
+			}
+			candidate = strings.Join(parts, "/")
+		}
+		if ok, _ := filepath.Match(pattern, candidate); ok {
+			level = rule.level
+			matched = true
+		}
+	}
+	return level, matched
+}
+
+// resolveVerbosity determines the effective verbosity level for the caller
+// at the given skip depth, consulting the per-PC cache before falling back
+// to a vmodule glob match and the global threshold.
+func resolveVerbosity(skip int) int32 {
+	pc, file, _, ok := runtime.Caller(skip)
+	if !ok {
+		return atomic.LoadInt32(&globalVerbosity)
+	}
+
+	epoch := atomic.LoadInt32(&vPCCacheEpoch)
+	if cached, ok := vPCCache.Load(pc); ok {
+		entry := cached.(vCacheEntry)
+		if entry.epoch == epoch {
+			return entry.level
+		}
+	}
+
+	level := atomic.LoadInt32(&globalVerbosity)
+	if override, matched := vmoduleLevelForFile(file); matched {
+		level = override
+	}
+	vPCCache.Store(pc, vCacheEntry{epoch: epoch, level: level})
+	return level
+}
+
+// V reports whether verbosity level `level` is enabled for the caller,
+// honoring any -vmodule override for the caller's file. Usage:
+//
+//	logging.V(2).Infof("cache miss for key %q", key)
+//
+// The returned Verbose logs through the default logger; use
+// StandardLogger.V to gate on a specific logger (e.g. one built via With()).
+func V(level int) Verbose {
+	return Verbose{enabled: int32(level) <= resolveVerbosity(2), logger: GetLogger()}
+}
+
+// V reports whether verbosity level `level` is enabled for the caller on
+// this specific logger. The verbosity gate itself is global (there is one
+// -vmodule table per process), matching glog/klog; only the logger used to
+// emit the record (and its fields/format/sinks) is per-receiver.
+func (l *StandardLogger) V(level int) Verbose {
+	return Verbose{enabled: int32(level) <= resolveVerbosity(2), logger: l}
+}
+
+// Info logs v at INFO level if the Verbose gate is enabled; it is a no-op
+// otherwise.
+func (v Verbose) Info(args ...interface{}) {
+	if !v.enabled {
+		return
+	}
+	message := fmt.Sprintln(args...)
+	message = message[:len(message)-1]
+	v.logger.logAtVerbose(LevelInfo, message)
+}
+
+// Infof is the formatted equivalent of Info.
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if !v.enabled {
+		return
+	}
+	v.logger.logAtVerbose(LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// logAtVerbose renders and writes a record on behalf of a Verbose gate. The
+// skip depth accounts for the Verbose.Info(f) -> logAtVerbose chain.
+func (l *StandardLogger) logAtVerbose(level LogLevel, message string) {
+	l.write(l.render(level, message, 4))
+	l.dispatchSinks(level, message, l.fields, 4)
+}
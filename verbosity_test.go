@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestLogger(service string) *StandardLogger {
+	return &StandardLogger{
+		serviceName: service,
+		logger:      log.New(io.Discard, "", 0),
+		format:      FormatText,
+		sinksMu:     &sync.RWMutex{},
+	}
+}
+
+// TestStandardLoggerVUsesReceiver reproduces a maintainer-filed bug:
+// l.V(n) used to always log through GetLogger() (the package default)
+// instead of l, silently dropping any fields/sinks attached to l.
+func TestStandardLoggerVUsesReceiver(t *testing.T) {
+	SetVerbosity(1)
+	defer SetVerbosity(0)
+
+	base := newTestLogger("svc-a")
+	child := base.With(F("request_id", "abc-123"))
+
+	mem := NewMemorySink(10)
+	child.AddSink(mem, LevelDebug)
+
+	child.V(1).Infof("cache miss for key %q", "k")
+
+	records := mem.Records()
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+
+	found := false
+	for _, f := range records[0].Fields {
+		if f.Key == "request_id" && f.Value == "abc-123" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("record fields = %v, want request_id=abc-123 (V() must log through the receiver logger)", records[0].Fields)
+	}
+}
+
+// TestResolveVerbosityConcurrentWithSetVerbosity exercises the data race a
+// maintainer reported between SetVerbosity/SetVModule (which used to swap
+// vPCCache out from under concurrent readers) and resolveVerbosity, called
+// from the V() hot path. Run with -race to catch a regression.
+func TestResolveVerbosityConcurrentWithSetVerbosity(t *testing.T) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				SetVerbosity(i % 3)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = V(1)
+			}
+		}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	close(done)
+	wg.Wait()
+	SetVerbosity(0)
+}